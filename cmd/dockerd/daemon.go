@@ -68,6 +68,7 @@ func NewDaemonCli() *DaemonCli {
 	daemonConfig := new(daemon.Config)
 	daemonConfig.LogConfig.Config = make(map[string]string)
 	daemonConfig.ClusterOpts = make(map[string]string)
+	daemonConfig.TrustedKeys = make(map[string]string)
 
 	if runtime.GOOS != "linux" {
 		daemonConfig.V2Only = true
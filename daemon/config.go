@@ -44,6 +44,7 @@ var flatOptions = map[string]bool{
 	"cluster-store-opts": true,
 	"log-opts":           true,
 	"runtimes":           true,
+	"trusted-keys":       true,
 }
 
 // LogConfig represents the default log configuration.
@@ -118,6 +119,14 @@ type CommonConfig struct {
 	// may take place at a time for each push.
 	MaxConcurrentUploads *int `json:"max-concurrent-uploads,omitempty"`
 
+	// TrustedKeys maps registry hostnames to files holding the public keys
+	// that image manifests from that registry must be signed with.
+	TrustedKeys map[string]string `json:"trusted-keys,omitempty"`
+
+	// SignatureStore is the directory holding detached signatures for
+	// manifest digests. It defaults to a directory under the daemon root.
+	SignatureStore string `json:"signature-store,omitempty"`
+
 	Debug     bool     `json:"debug,omitempty"`
 	Hosts     []string `json:"hosts,omitempty"`
 	LogLevel  string   `json:"log-level,omitempty"`
@@ -164,6 +173,8 @@ func (config *Config) InstallCommonFlags(cmd *flag.FlagSet, usageFn func(string)
 	cmd.StringVar(&config.ClusterStore, []string{"-cluster-store"}, "", usageFn("Set the cluster store"))
 	cmd.Var(opts.NewNamedMapOpts("cluster-store-opts", config.ClusterOpts, nil), []string{"-cluster-store-opt"}, usageFn("Set cluster store options"))
 	cmd.StringVar(&config.CorsHeaders, []string{"-api-cors-header"}, "", usageFn("Set CORS headers in the remote API"))
+	cmd.Var(opts.NewNamedMapOpts("trusted-keys", config.TrustedKeys, nil), []string{"-trusted-key"}, usageFn("Require images from a registry to be signed by a key in a file (registry=file)"))
+	cmd.StringVar(&config.SignatureStore, []string{"-signature-store"}, "", usageFn("Directory holding detached image signatures"))
 	cmd.IntVar(&maxConcurrentDownloads, []string{"-max-concurrent-downloads"}, defaultMaxConcurrentDownloads, usageFn("Set the max concurrent downloads for each pull"))
 	cmd.IntVar(&maxConcurrentUploads, []string{"-max-concurrent-uploads"}, defaultMaxConcurrentUploads, usageFn("Set the max concurrent uploads for each push"))
 
@@ -75,6 +75,10 @@ func (daemon *Daemon) create(params types.ContainerCreateConfig, managed bool) (
 			return nil, err
 		}
 		imgID = img.ID()
+
+		if err := daemon.verifyImageSignature(imgID); err != nil {
+			return nil, err
+		}
 	}
 
 	if err := daemon.mergeAndVerifyConfig(params.Config, img); err != nil {
@@ -32,6 +32,7 @@ import (
 	// register graph drivers
 	_ "github.com/docker/docker/daemon/graphdriver/register"
 	dmetadata "github.com/docker/docker/distribution/metadata"
+	"github.com/docker/docker/distribution/signature"
 	"github.com/docker/docker/distribution/xfer"
 	"github.com/docker/docker/image"
 	"github.com/docker/docker/layer"
@@ -77,6 +78,7 @@ type Daemon struct {
 	downloadManager           *xfer.LayerDownloadManager
 	uploadManager             *xfer.LayerUploadManager
 	distributionMetadataStore dmetadata.Store
+	signaturePolicy           *signature.Policy
 	trustKey                  libtrust.PrivateKey
 	idIndex                   *truncindex.TruncIndex
 	configStore               *Config
@@ -542,6 +544,20 @@ func NewDaemon(config *Config, registryService registry.Service, containerdRemot
 		return nil, err
 	}
 
+	if len(config.TrustedKeys) > 0 {
+		signatureRoot := config.SignatureStore
+		if signatureRoot == "" {
+			signatureRoot = filepath.Join(trustDir, "signatures")
+		}
+		signatureStore, err := signature.NewStore(signatureRoot)
+		if err != nil {
+			return nil, err
+		}
+		if d.signaturePolicy, err = signature.NewPolicy(signatureStore, config.TrustedKeys); err != nil {
+			return nil, err
+		}
+	}
+
 	eventsService := events.New()
 
 	referenceStore, err := reference.NewReferenceStore(filepath.Join(imageRoot, "repositories.json"))
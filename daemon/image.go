@@ -63,6 +63,32 @@ func (daemon *Daemon) GetImage(refOrID string) (*image.Image, error) {
 	return daemon.imageStore.Get(imgID)
 }
 
+// verifyImageSignature checks that an image referenced from a registry with
+// trusted keys was pulled by a manifest digest carrying a trusted signature.
+// Images that are not referenced from such a registry are not restricted.
+func (daemon *Daemon) verifyImageSignature(imgID image.ID) error {
+	if daemon.signaturePolicy == nil {
+		return nil
+	}
+
+	var restricted reference.Named
+	for _, ref := range daemon.referenceStore.References(imgID) {
+		if !daemon.signaturePolicy.Requires(ref) {
+			continue
+		}
+		restricted = ref
+		if canonical, ok := ref.(reference.Canonical); ok {
+			if err := daemon.signaturePolicy.Verify(canonical, canonical.Digest()); err == nil {
+				return nil
+			}
+		}
+	}
+	if restricted == nil {
+		return nil
+	}
+	return fmt.Errorf("image %s (%s) has no trusted signature", restricted.String(), imgID)
+}
+
 // GetImageOnBuild looks up a Docker image referenced by `name`.
 func (daemon *Daemon) GetImageOnBuild(name string) (builder.Image, error) {
 	img, err := daemon.GetImage(name)
@@ -5,6 +5,7 @@ import (
 
 	"github.com/docker/distribution/digest"
 	"github.com/docker/docker/layer"
+	"github.com/docker/docker/reference"
 )
 
 // V2MetadataService maps layer IDs to a set of known metadata for
@@ -19,6 +20,19 @@ type V2Metadata struct {
 	SourceRepository string
 }
 
+// SourceRegistry returns the hostname of the registry the layer was pulled
+// from or pushed to, or an empty string if the source repository is unknown.
+func (meta V2Metadata) SourceRegistry() string {
+	if meta.SourceRepository == "" {
+		return ""
+	}
+	named, err := reference.ParseNamed(meta.SourceRepository)
+	if err != nil {
+		return ""
+	}
+	return named.Hostname()
+}
+
 // maxMetadata is the number of metadata entries to keep per layer DiffID.
 const maxMetadata = 50
 
@@ -60,6 +74,23 @@ func (serv *V2MetadataService) GetMetadata(diffID layer.DiffID) ([]V2Metadata, e
 	return metadata, nil
 }
 
+// GetMetadataForRegistry finds the metadata associated with a layer DiffID
+// whose source repository lives on the registry identified by hostname.
+func (serv *V2MetadataService) GetMetadataForRegistry(diffID layer.DiffID, hostname string) ([]V2Metadata, error) {
+	all, err := serv.GetMetadata(diffID)
+	if err != nil {
+		return nil, err
+	}
+
+	var metadata []V2Metadata
+	for _, meta := range all {
+		if meta.SourceRegistry() == hostname {
+			metadata = append(metadata, meta)
+		}
+	}
+	return metadata, nil
+}
+
 // GetDiffID finds a layer DiffID from a digest.
 func (serv *V2MetadataService) GetDiffID(dgst digest.Digest) (layer.DiffID, error) {
 	diffIDBytes, err := serv.store.Get(serv.digestNamespace(), serv.digestKey(dgst))
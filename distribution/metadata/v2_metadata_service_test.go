@@ -113,3 +113,45 @@ func randomDigest() digest.Digest {
 	d := hex.EncodeToString(b[:])
 	return digest.Digest("sha256:" + d)
 }
+
+func TestV2MetadataServiceGetMetadataForRegistry(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "v2-metadata-registry-test")
+	if err != nil {
+		t.Fatalf("could not create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	metadataStore, err := NewFSMetadataStore(tmpDir)
+	if err != nil {
+		t.Fatalf("could not create metadata store: %v", err)
+	}
+	V2MetadataService := NewV2MetadataService(metadataStore)
+
+	diffID := layer.DiffID("sha256:a3ed95caeb02ffe68cdd9fd84406680ae93d633cb16422d00e8a7c22955b46d4")
+	entries := []V2Metadata{
+		{Digest: randomDigest(), SourceRepository: "docker.io/library/busybox"},
+		{Digest: randomDigest(), SourceRepository: "registry.example.com:5000/team/app"},
+		{Digest: randomDigest()},
+		{Digest: randomDigest(), SourceRepository: "registry.example.com:5000/team/base"},
+	}
+	for _, meta := range entries {
+		if err := V2MetadataService.Add(diffID, meta); err != nil {
+			t.Fatalf("error calling Add: %v", err)
+		}
+	}
+
+	expectedRegistries := []string{"docker.io", "registry.example.com:5000", "", "registry.example.com:5000"}
+	for i, meta := range entries {
+		if registry := meta.SourceRegistry(); registry != expectedRegistries[i] {
+			t.Fatalf("expected source registry %q for %q, got %q", expectedRegistries[i], meta.SourceRepository, registry)
+		}
+	}
+
+	metadata, err := V2MetadataService.GetMetadataForRegistry(diffID, "registry.example.com:5000")
+	if err != nil {
+		t.Fatalf("error calling GetMetadataForRegistry: %v", err)
+	}
+	if !reflect.DeepEqual(metadata, []V2Metadata{entries[1], entries[3]}) {
+		t.Fatalf("GetMetadataForRegistry returned unexpected entries: %v", metadata)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/docker/distribution/digest"
 	"github.com/docker/docker/api"
 	"github.com/docker/docker/distribution/metadata"
+	"github.com/docker/docker/distribution/signature"
 	"github.com/docker/docker/distribution/xfer"
 	"github.com/docker/docker/image"
 	"github.com/docker/docker/pkg/progress"
@@ -40,6 +41,9 @@ type ImagePullConfig struct {
 	ReferenceStore reference.Store
 	// DownloadManager manages concurrent pulls.
 	DownloadManager *xfer.LayerDownloadManager
+	// SignaturePolicy, if set, is used to verify that pulled manifests
+	// carry a trusted signature.
+	SignaturePolicy *signature.Policy
 }
 
 // Puller is an interface that abstracts pulling for different API versions.
@@ -40,6 +40,10 @@ func (p *v1Puller) Pull(ctx context.Context, ref reference.Named) error {
 		// Allowing fallback, because HTTPS v1 is before HTTP v2
 		return fallbackError{err: ErrNoSupport{Err: errors.New("Cannot pull by digest with v1 registry")}}
 	}
+	if p.config.SignaturePolicy != nil && p.config.SignaturePolicy.Requires(ref) {
+		// v1 registries have no manifest digests to verify signatures against
+		return fallbackError{err: ErrNoSupport{Err: errors.New("Cannot verify image signatures with v1 registry")}}
+	}
 
 	tlsConfig, err := p.config.RegistryService.TLSConfig(p.repoInfo.Index.Name)
 	if err != nil {
@@ -360,6 +360,16 @@ func (p *v2Puller) pullV2Tag(ctx context.Context, ref reference.Named) (tagUpdat
 	// the other side speaks the v2 protocol.
 	p.confirmedV2 = true
 
+	if p.config.SignaturePolicy != nil && p.config.SignaturePolicy.Requires(ref) {
+		dgst, err := manifestDigest(manifest)
+		if err != nil {
+			return false, err
+		}
+		if err := p.config.SignaturePolicy.Verify(ref, dgst); err != nil {
+			return false, err
+		}
+	}
+
 	logrus.Debugf("Pulling ref from V2 registry: %s", ref.String())
 	progress.Message(p.config.ProgressOutput, tagOrDigest, "Pulling from "+p.repo.Named().Name())
 
@@ -731,6 +741,18 @@ func schema2ManifestDigest(ref reference.Named, mfst distribution.Manifest) (dig
 // (even if confirmedV2 has been set already), and if so, wraps the error in
 // a fallbackError with confirmedV2 set to false. Otherwise, it returns the
 // error unmodified.
+// manifestDigest returns the digest the registry addresses manifest by.
+func manifestDigest(manifest distribution.Manifest) (digest.Digest, error) {
+	if signed, ok := manifest.(*schema1.SignedManifest); ok {
+		return digest.FromBytes(signed.Canonical), nil
+	}
+	_, payload, err := manifest.Payload()
+	if err != nil {
+		return "", err
+	}
+	return digest.FromBytes(payload), nil
+}
+
 func allowV1Fallback(err error) error {
 	switch v := err.(type) {
 	case errcode.Errors:
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sort"
+	"strings"
 	"sync"
 
 	"github.com/Sirupsen/logrus"
@@ -56,8 +58,16 @@ type pushState struct {
 	// confirmedV2 is set to true if we confirm we're talking to a v2
 	// registry. This is used to limit fallbacks to the v1 protocol.
 	confirmedV2 bool
+	// skipped, mounted and uploaded count the layers that were found on
+	// the remote side, mounted from another repository, and uploaded.
+	skipped, mounted, uploaded int
 }
 
+// maxExistenceChecks is the number of blobsums recorded against other
+// repositories that are checked for existence in the target repository
+// before a layer is uploaded.
+const maxExistenceChecks = 3
+
 func (p *v2Pusher) Push(ctx context.Context) (err error) {
 	p.pushState.remoteLayers = make(map[layer.DiffID]distribution.Descriptor)
 
@@ -151,10 +161,19 @@ func (p *v2Pusher) pushV2Tag(ctx context.Context, ref reference.NamedTagged, ima
 		l = l.Parent()
 	}
 
+	p.pushState.Lock()
+	skipped, mounted, uploaded := p.pushState.skipped, p.pushState.mounted, p.pushState.uploaded
+	p.pushState.Unlock()
+
 	if err := p.config.UploadManager.Upload(ctx, descriptors, p.config.ProgressOutput); err != nil {
 		return err
 	}
 
+	p.pushState.Lock()
+	skipped, mounted, uploaded = p.pushState.skipped-skipped, p.pushState.mounted-mounted, p.pushState.uploaded-uploaded
+	p.pushState.Unlock()
+	progress.Messagef(p.config.ProgressOutput, "", "%s: layers: %d pushed, %d mounted, %d skipped", ref.Tag(), uploaded, mounted, skipped)
+
 	// Try schema2 first
 	builder := schema2.NewManifestBuilder(p.repo.Blobs(ctx), img.RawJSON())
 	manifest, err := manifestFromBuilder(ctx, builder, descriptors)
@@ -250,6 +269,9 @@ func (pd *v2PushDescriptor) Upload(ctx context.Context, progressOutput progress.
 	if fs, ok := pd.layer.(distribution.Describable); ok {
 		if d := fs.Descriptor(); len(d.URLs) > 0 {
 			progress.Update(progressOutput, pd.ID(), "Skipped foreign layer")
+			pd.pushState.Lock()
+			pd.pushState.skipped++
+			pd.pushState.Unlock()
 			return d, nil
 		}
 	}
@@ -260,6 +282,7 @@ func (pd *v2PushDescriptor) Upload(ctx context.Context, progressOutput progress.
 	if descriptor, ok := pd.pushState.remoteLayers[diffID]; ok {
 		// it is already known that the push is not needed and
 		// therefore doing a stat is unnecessary
+		pd.pushState.skipped++
 		pd.pushState.Unlock()
 		progress.Update(progressOutput, pd.ID(), "Layer already exists")
 		return descriptor, nil
@@ -269,7 +292,7 @@ func (pd *v2PushDescriptor) Upload(ctx context.Context, progressOutput progress.
 	// Do we have any metadata associated with this layer's DiffID?
 	v2Metadata, err := pd.v2MetadataService.GetMetadata(diffID)
 	if err == nil {
-		descriptor, exists, err := layerAlreadyExists(ctx, v2Metadata, pd.repoInfo, pd.repo, pd.pushState)
+		descriptor, exists, err := layerAlreadyExists(ctx, v2Metadata, pd.repoInfo, pd.repo, false)
 		if err != nil {
 			progress.Update(progressOutput, pd.ID(), "Image push failed")
 			return distribution.Descriptor{}, retryOnError(err)
@@ -278,6 +301,7 @@ func (pd *v2PushDescriptor) Upload(ctx context.Context, progressOutput progress.
 			progress.Update(progressOutput, pd.ID(), "Layer already exists")
 			pd.pushState.Lock()
 			pd.pushState.remoteLayers[diffID] = descriptor
+			pd.pushState.skipped++
 			pd.pushState.Unlock()
 			return descriptor, nil
 		}
@@ -289,35 +313,18 @@ func (pd *v2PushDescriptor) Upload(ctx context.Context, progressOutput progress.
 	// then push the blob.
 	bs := pd.repo.Blobs(ctx)
 
-	var layerUpload distribution.BlobWriter
-	mountAttemptsRemaining := 3
-
 	// Attempt to find another repository in the same registry to mount the layer
 	// from to avoid an unnecessary upload.
-	// Note: metadata is stored from oldest to newest, so we iterate through this
-	// slice in reverse to maximize our chances of the blob still existing in the
-	// remote repository.
-	for i := len(v2Metadata) - 1; i >= 0 && mountAttemptsRemaining > 0; i-- {
-		mountFrom := v2Metadata[i]
-
+	for _, mountFrom := range getRepositoryMountCandidates(pd.repoInfo, v2Metadata) {
 		sourceRepo, err := reference.ParseNamed(mountFrom.SourceRepository)
 		if err != nil {
 			continue
 		}
-		if pd.repoInfo.Hostname() != sourceRepo.Hostname() {
-			// don't mount blobs from another registry
-			continue
-		}
-
-		namedRef, err := reference.WithName(mountFrom.SourceRepository)
-		if err != nil {
-			continue
-		}
 
 		// TODO (brianbland): We need to construct a reference where the Name is
 		// only the full remote name, so clean this up when distribution has a
 		// richer reference package
-		remoteRef, err := distreference.WithName(namedRef.RemoteName())
+		remoteRef, err := distreference.WithName(sourceRepo.RemoteName())
 		if err != nil {
 			continue
 		}
@@ -329,7 +336,7 @@ func (pd *v2PushDescriptor) Upload(ctx context.Context, progressOutput progress.
 
 		logrus.Debugf("attempting to mount layer %s (%s) from %s", diffID, mountFrom.Digest, sourceRepo.FullName())
 
-		layerUpload, err = bs.Create(ctx, client.WithMountFrom(canonicalRef))
+		layerUpload, err := bs.Create(ctx, client.WithMountFrom(canonicalRef))
 		switch err := err.(type) {
 		case distribution.ErrBlobMounted:
 			progress.Updatef(progressOutput, pd.ID(), "Mounted from %s", err.From.Name())
@@ -339,6 +346,7 @@ func (pd *v2PushDescriptor) Upload(ctx context.Context, progressOutput progress.
 			pd.pushState.Lock()
 			pd.pushState.confirmedV2 = true
 			pd.pushState.remoteLayers[diffID] = err.Descriptor
+			pd.pushState.mounted++
 			pd.pushState.Unlock()
 
 			// Cache mapping from this layer's DiffID to the blobsum
@@ -347,21 +355,43 @@ func (pd *v2PushDescriptor) Upload(ctx context.Context, progressOutput progress.
 			}
 			return err.Descriptor, nil
 		case nil:
-			// blob upload session created successfully, so begin the upload
-			mountAttemptsRemaining = 0
-		default:
-			// unable to mount layer from this repository, so this source mapping is no longer valid
-			logrus.Debugf("unassociating layer %s (%s) with %s", diffID, mountFrom.Digest, mountFrom.SourceRepository)
-			pd.v2MetadataService.Remove(mountFrom)
-			mountAttemptsRemaining--
+			// The registry opened an upload session instead of mounting the
+			// blob. Cancel it so the next candidate can be tried.
+			if err := layerUpload.Cancel(ctx); err != nil {
+				logrus.Debugf("failed to cancel upload session for %s: %v", diffID, err)
+			}
 		}
+
+		// unable to mount layer from this repository, so this source mapping is no longer valid
+		logrus.Debugf("unassociating layer %s (%s) with %s", diffID, mountFrom.Digest, mountFrom.SourceRepository)
+		pd.v2MetadataService.Remove(mountFrom)
 	}
 
-	if layerUpload == nil {
-		layerUpload, err = bs.Create(ctx)
+	// The blob may already have been pushed to this repository under a digest
+	// recorded against another repository or registry.
+	if len(v2Metadata) > 0 {
+		descriptor, exists, err := layerAlreadyExists(ctx, v2Metadata, pd.repoInfo, pd.repo, true)
 		if err != nil {
+			progress.Update(progressOutput, pd.ID(), "Image push failed")
 			return distribution.Descriptor{}, retryOnError(err)
 		}
+		if exists {
+			progress.Update(progressOutput, pd.ID(), "Layer already exists")
+			pd.pushState.Lock()
+			pd.pushState.remoteLayers[diffID] = descriptor
+			pd.pushState.skipped++
+			pd.pushState.Unlock()
+
+			if err := pd.v2MetadataService.Add(diffID, metadata.V2Metadata{Digest: descriptor.Digest, SourceRepository: pd.repoInfo.FullName()}); err != nil {
+				return distribution.Descriptor{}, xfer.DoNotRetry{Err: err}
+			}
+			return descriptor, nil
+		}
+	}
+
+	layerUpload, err := bs.Create(ctx)
+	if err != nil {
+		return distribution.Descriptor{}, retryOnError(err)
 	}
 	defer layerUpload.Close()
 
@@ -414,6 +444,7 @@ func (pd *v2PushDescriptor) Upload(ctx context.Context, progressOutput progress.
 		Size:      nn,
 	}
 	pd.pushState.remoteLayers[diffID] = descriptor
+	pd.pushState.uploaded++
 
 	pd.pushState.Unlock()
 
@@ -430,13 +461,25 @@ func (pd *v2PushDescriptor) Descriptor() distribution.Descriptor {
 
 // layerAlreadyExists checks if the registry already know about any of the
 // metadata passed in the "metadata" slice. If it finds one that the registry
-// knows about, it returns the known digest and "true".
-func layerAlreadyExists(ctx context.Context, metadata []metadata.V2Metadata, repoInfo reference.Named, repo distribution.Repository, pushState *pushState) (distribution.Descriptor, bool, error) {
-	for _, meta := range metadata {
-		// Only check blobsums that are known to this repository or have an unknown source
-		if meta.SourceRepository != "" && meta.SourceRepository != repoInfo.FullName() {
+// knows about, it returns the known digest and "true". Unless
+// checkOtherRepositories is set, only blobsums that are known to this
+// repository or have an unknown source are checked. Otherwise, up to
+// maxExistenceChecks blobsums recorded against other repositories, including
+// repositories on other registries, are checked, most recent first.
+func layerAlreadyExists(ctx context.Context, metadata []metadata.V2Metadata, repoInfo reference.Named, repo distribution.Repository, checkOtherRepositories bool) (distribution.Descriptor, bool, error) {
+	checksRemaining := maxExistenceChecks
+	for i := len(metadata) - 1; i >= 0; i-- {
+		meta := metadata[i]
+		isOwn := meta.SourceRepository == "" || meta.SourceRepository == repoInfo.FullName()
+		if isOwn == checkOtherRepositories {
 			continue
 		}
+		if checkOtherRepositories {
+			if checksRemaining == 0 {
+				break
+			}
+			checksRemaining--
+		}
 		descriptor, err := repo.Blobs(ctx).Stat(ctx, meta.Digest)
 		switch err {
 		case nil:
@@ -450,3 +493,67 @@ func layerAlreadyExists(ctx context.Context, metadata []metadata.V2Metadata, rep
 	}
 	return distribution.Descriptor{}, false, nil
 }
+
+// getRepositoryMountCandidates returns the metadata entries that refer to
+// other repositories on the same registry as repoInfo, ordered by the
+// likelihood of a successful mount. Repositories sharing more leading path
+// components with the target come first; ties are broken in favour of the
+// most recently recorded entry.
+func getRepositoryMountCandidates(repoInfo reference.Named, v2Metadata []metadata.V2Metadata) []metadata.V2Metadata {
+	candidates := make([]metadata.V2Metadata, 0, len(v2Metadata))
+	// metadata is stored from oldest to newest, so iterate in reverse to
+	// maximize our chances of the blob still existing in the remote
+	// repository.
+	for i := len(v2Metadata) - 1; i >= 0; i-- {
+		meta := v2Metadata[i]
+		if meta.SourceRepository == "" || meta.SourceRepository == repoInfo.FullName() {
+			continue
+		}
+		if meta.SourceRegistry() != repoInfo.Hostname() {
+			// don't mount blobs from another registry
+			continue
+		}
+		candidates = append(candidates, meta)
+	}
+
+	sort.Stable(byMountLikelihood{
+		candidates: candidates,
+		target:     repoInfo.RemoteName(),
+	})
+	return candidates
+}
+
+// byMountLikelihood sorts mount candidates by the number of leading path
+// components their repository shares with the target repository.
+type byMountLikelihood struct {
+	candidates []metadata.V2Metadata
+	target     string
+}
+
+func (s byMountLikelihood) Len() int { return len(s.candidates) }
+func (s byMountLikelihood) Swap(i, j int) {
+	s.candidates[i], s.candidates[j] = s.candidates[j], s.candidates[i]
+}
+func (s byMountLikelihood) Less(i, j int) bool {
+	return s.matchingPathComponents(i) > s.matchingPathComponents(j)
+}
+
+func (s byMountLikelihood) matchingPathComponents(i int) int {
+	named, err := reference.ParseNamed(s.candidates[i].SourceRepository)
+	if err != nil {
+		return 0
+	}
+	return numOfMatchingPathComponents(named.RemoteName(), s.target)
+}
+
+// numOfMatchingPathComponents returns the number of leading path components
+// that the repository names a and b have in common.
+func numOfMatchingPathComponents(a, b string) int {
+	aComponents := strings.Split(a, "/")
+	bComponents := strings.Split(b, "/")
+	n := 0
+	for n < len(aComponents) && n < len(bComponents) && aComponents[n] == bComponents[n] {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,63 @@
+package distribution
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/docker/distribution/digest"
+	"github.com/docker/docker/distribution/metadata"
+	"github.com/docker/docker/reference"
+)
+
+func TestNumOfMatchingPathComponents(t *testing.T) {
+	for _, tc := range []struct {
+		a, b     string
+		expected int
+	}{
+		{"library/busybox", "library/busybox", 2},
+		{"library/busybox", "library/ubuntu", 1},
+		{"team/app/web", "team/app/db", 2},
+		{"team/app", "other/app", 0},
+		{"busybox", "busybox/extra", 1},
+	} {
+		if n := numOfMatchingPathComponents(tc.a, tc.b); n != tc.expected {
+			t.Errorf("numOfMatchingPathComponents(%q, %q) = %d, expected %d", tc.a, tc.b, n, tc.expected)
+		}
+	}
+}
+
+func TestGetRepositoryMountCandidates(t *testing.T) {
+	repoInfo, err := reference.ParseNamed("registry.example.com/team/app/web")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	meta := func(repo string, dgst digest.Digest) metadata.V2Metadata {
+		return metadata.V2Metadata{Digest: dgst, SourceRepository: repo}
+	}
+	var (
+		dgst1 = digest.Digest("sha256:f0cd5ca10b07f35512fc2f1cbf9a6cefbdb5cba70ac6b0c9e5988f4497f71937")
+		dgst2 = digest.Digest("sha256:9e3447ca24cb96d86ebd5960cb34d1299b07e0a0e03801d90b9969a2c187dd6e")
+	)
+
+	// metadata is ordered from oldest to newest
+	candidates := getRepositoryMountCandidates(repoInfo, []metadata.V2Metadata{
+		meta("registry.example.com/other/base", dgst1),
+		meta("registry.example.com/team/app/db", dgst1),
+		meta("docker.io/team/app/db", dgst1),
+		meta("registry.example.com/team/app/web", dgst1),
+		{Digest: dgst2},
+		meta("registry.example.com/team/lib", dgst2),
+		meta("registry.example.com/other/tools", dgst2),
+	})
+
+	expected := []metadata.V2Metadata{
+		meta("registry.example.com/team/app/db", dgst1),
+		meta("registry.example.com/team/lib", dgst2),
+		meta("registry.example.com/other/tools", dgst2),
+		meta("registry.example.com/other/base", dgst1),
+	}
+	if !reflect.DeepEqual(candidates, expected) {
+		t.Fatalf("unexpected mount candidates:\n got: %v\nwant: %v", candidates, expected)
+	}
+}
@@ -0,0 +1,66 @@
+package signature
+
+import (
+	"fmt"
+
+	"github.com/docker/distribution/digest"
+	"github.com/docker/docker/reference"
+	"github.com/docker/libtrust"
+)
+
+// Policy decides which images require a signature and verifies them. Images
+// from a registry with trusted keys must carry a signature made by one of
+// those keys; images from any other registry are accepted as-is.
+type Policy struct {
+	store *Store
+	keys  map[string][]libtrust.PublicKey
+}
+
+// NewPolicy creates a verification policy. keyFiles maps registry hostnames
+// to files holding the public keys trusted for that registry, in PEM or JWK
+// set format.
+func NewPolicy(store *Store, keyFiles map[string]string) (*Policy, error) {
+	p := &Policy{
+		store: store,
+		keys:  make(map[string][]libtrust.PublicKey),
+	}
+	for hostname, keyFile := range keyFiles {
+		keys, err := libtrust.LoadKeySetFile(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not load trusted keys for %s from %s: %v", hostname, keyFile, err)
+		}
+		if len(keys) == 0 {
+			return nil, fmt.Errorf("no trusted keys for %s found in %s", hostname, keyFile)
+		}
+		p.keys[hostname] = keys
+	}
+	return p, nil
+}
+
+// Requires returns true if images from the registry of ref must be signed.
+func (p *Policy) Requires(ref reference.Named) bool {
+	_, ok := p.keys[ref.Hostname()]
+	return ok
+}
+
+// Verify checks that the manifest digest dgst, pulled or run from ref, is
+// signed by a key trusted for the registry of ref.
+func (p *Policy) Verify(ref reference.Named, dgst digest.Digest) error {
+	keys, ok := p.keys[ref.Hostname()]
+	if !ok {
+		return nil
+	}
+
+	signatures, err := p.store.Get(dgst)
+	if err != nil {
+		return err
+	}
+	for _, sig := range signatures {
+		for _, key := range keys {
+			if sig.KeyID == key.KeyID() && sig.Verify(key) == nil {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("%s@%s: %v", ref.FullName(), dgst, ErrNoSignature)
+}
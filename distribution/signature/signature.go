@@ -0,0 +1,55 @@
+// Package signature verifies detached signatures over image manifest
+// digests against keys trusted for a registry, without relying on a
+// Notary server.
+package signature
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/docker/distribution/digest"
+	"github.com/docker/libtrust"
+)
+
+// ErrNoSignature is returned when no signature made by a trusted key exists
+// for a manifest digest.
+var ErrNoSignature = errors.New("no trusted signature found")
+
+// Signature is a detached signature over a manifest digest.
+type Signature struct {
+	// Digest is the manifest digest that was signed.
+	Digest digest.Digest `json:"digest"`
+	// KeyID identifies the key that produced the signature.
+	KeyID string `json:"keyid"`
+	// Algorithm is the signature algorithm, as reported by libtrust.
+	Algorithm string `json:"alg"`
+	// Signature holds the raw signature bytes.
+	Signature []byte `json:"signature"`
+}
+
+// Sign creates a detached signature over dgst using key.
+func Sign(key libtrust.PrivateKey, dgst digest.Digest) (Signature, error) {
+	if err := dgst.Validate(); err != nil {
+		return Signature{}, err
+	}
+	sig, alg, err := key.Sign(strings.NewReader(dgst.String()), crypto.SHA256)
+	if err != nil {
+		return Signature{}, err
+	}
+	return Signature{
+		Digest:    dgst,
+		KeyID:     key.KeyID(),
+		Algorithm: alg,
+		Signature: sig,
+	}, nil
+}
+
+// Verify checks that the signature was made over its digest by key.
+func (s Signature) Verify(key libtrust.PublicKey) error {
+	if s.KeyID != key.KeyID() {
+		return fmt.Errorf("signature was made by key %s, not %s", s.KeyID, key.KeyID())
+	}
+	return key.Verify(strings.NewReader(s.Digest.String()), s.Algorithm, s.Signature)
+}
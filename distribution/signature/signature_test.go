@@ -0,0 +1,120 @@
+package signature
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/distribution/digest"
+	"github.com/docker/docker/reference"
+	"github.com/docker/libtrust"
+)
+
+func writePublicKeyFile(t *testing.T, dir string, key libtrust.PrivateKey) string {
+	block, err := key.PublicKey().PEMBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyFile := filepath.Join(dir, key.KeyID()+".pem")
+	if err := ioutil.WriteFile(keyFile, pem.EncodeToMemory(block), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return keyFile
+}
+
+func TestPolicyVerify(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "signature-policy-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	trustedKey, err := libtrust.GenerateECP256PrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := libtrust.GenerateECP256PrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := NewStore(filepath.Join(tmpDir, "signatures"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy, err := NewPolicy(store, map[string]string{
+		"registry.example.com": writePublicKeyFile(t, tmpDir, trustedKey),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signedRef, err := reference.ParseNamed("registry.example.com/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsignedRef, err := reference.ParseNamed("docker.io/library/busybox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !policy.Requires(signedRef) {
+		t.Fatal("expected signatures to be required for registry.example.com")
+	}
+	if policy.Requires(unsignedRef) {
+		t.Fatal("expected signatures not to be required for docker.io")
+	}
+
+	dgst := digest.FromBytes([]byte("manifest"))
+	if err := policy.Verify(signedRef, dgst); err == nil {
+		t.Fatal("expected verification of an unsigned digest to fail")
+	}
+	if err := policy.Verify(unsignedRef, dgst); err != nil {
+		t.Fatalf("unexpected error verifying a digest from an unrestricted registry: %v", err)
+	}
+
+	// A signature by an untrusted key is not sufficient.
+	sig, err := Sign(otherKey, dgst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Add(sig); err != nil {
+		t.Fatal(err)
+	}
+	if err := policy.Verify(signedRef, dgst); err == nil {
+		t.Fatal("expected verification with an untrusted key to fail")
+	}
+
+	// Neither is a trusted signature over another digest.
+	forged, err := Sign(trustedKey, digest.FromBytes([]byte("other manifest")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged.Digest = dgst
+	if err := store.Add(forged); err != nil {
+		t.Fatal(err)
+	}
+	if err := policy.Verify(signedRef, dgst); err == nil {
+		t.Fatal("expected verification of a signature over another digest to fail")
+	}
+
+	sig, err = Sign(trustedKey, dgst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Add(sig); err != nil {
+		t.Fatal(err)
+	}
+	if err := policy.Verify(signedRef, dgst); err != nil {
+		t.Fatalf("unexpected verification error: %v", err)
+	}
+
+	signatures, err := store.Get(dgst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(signatures) != 2 {
+		t.Fatalf("expected 2 stored signatures, got %d", len(signatures))
+	}
+}
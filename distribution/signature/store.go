@@ -0,0 +1,98 @@
+package signature
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/docker/distribution/digest"
+	"github.com/docker/docker/pkg/ioutils"
+)
+
+// Store keeps detached signatures for manifest digests. Signatures for a
+// digest are stored in a directory named after the digest, one JSON file
+// per signing key, so that signatures produced elsewhere can be copied in
+// directly.
+type Store struct {
+	sync.RWMutex
+	root string
+}
+
+// NewStore creates a signature store rooted at root.
+func NewStore(root string) (*Store, error) {
+	if err := os.MkdirAll(root, 0700); err != nil {
+		return nil, err
+	}
+	return &Store{root: root}, nil
+}
+
+func (s *Store) dir(dgst digest.Digest) string {
+	return filepath.Join(s.root, string(dgst.Algorithm()), dgst.Hex())
+}
+
+// Get returns all signatures stored for dgst. Files that cannot be decoded,
+// or whose digest does not match, are skipped.
+func (s *Store) Get(dgst digest.Digest) ([]Signature, error) {
+	if err := dgst.Validate(); err != nil {
+		return nil, err
+	}
+
+	s.RLock()
+	defer s.RUnlock()
+
+	dir := s.dir(dgst)
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var signatures []Signature
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".json") {
+			continue
+		}
+		b, err := ioutil.ReadFile(filepath.Join(dir, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var sig Signature
+		if err := json.Unmarshal(b, &sig); err != nil || sig.Digest != dgst {
+			continue
+		}
+		signatures = append(signatures, sig)
+	}
+	return signatures, nil
+}
+
+// Add stores sig, replacing any signature previously stored for the same
+// digest and key.
+func (s *Store) Add(sig Signature) error {
+	if err := sig.Digest.Validate(); err != nil {
+		return err
+	}
+	b, err := json.Marshal(sig)
+	if err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	dir := s.dir(sig.Digest)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	return ioutils.AtomicWriteFile(filepath.Join(dir, keyFileName(sig.KeyID)), b, 0600)
+}
+
+// keyFileName turns a libtrust key ID, which contains colons, into a file
+// name that is valid on every platform.
+func keyFileName(keyID string) string {
+	return strings.Replace(keyID, ":", "-", -1) + ".json"
+}
@@ -42,6 +42,13 @@ func (l *tarexporter) Load(inTar io.ReadCloser, outStream io.Writer, quiet bool)
 	if err := chrootarchive.Untar(inTar, tmpDir, nil); err != nil {
 		return err
 	}
+	isOCI, err := isOCILayout(tmpDir)
+	if err != nil {
+		return err
+	}
+	if isOCI {
+		return l.ociLoad(tmpDir, outStream, progressOutput)
+	}
 	// read manifest, if no file then load in legacy mode
 	manifestPath, err := safePath(tmpDir, manifestFileName)
 	if err != nil {
@@ -0,0 +1,79 @@
+package tarexport
+
+import (
+	"github.com/docker/distribution/digest"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+	"github.com/docker/docker/reference"
+)
+
+// Files, media types and annotations of the OCI image layout.
+const (
+	ociLayoutFileName = "oci-layout"
+	ociIndexFileName  = "index.json"
+	ociBlobsDirName   = "blobs"
+
+	ociImageLayoutVersion = "1.0.0"
+
+	ociMediaTypeIndex    = "application/vnd.oci.image.index.v1+json"
+	ociMediaTypeManifest = "application/vnd.oci.image.manifest.v1+json"
+	ociMediaTypeConfig   = "application/vnd.oci.image.config.v1+json"
+	ociMediaTypeLayer    = "application/vnd.oci.image.layer.v1.tar"
+
+	// ociAnnotationRefName holds the tag of an image in the index.
+	ociAnnotationRefName = "org.opencontainers.image.ref.name"
+	// ociAnnotationImageName holds the full reference of an image in the
+	// index, as understood by other runtimes.
+	ociAnnotationImageName = "io.containerd.image.name"
+)
+
+type ociLayout struct {
+	ImageLayoutVersion string `json:"imageLayoutVersion"`
+}
+
+type ociPlatform struct {
+	Architecture string `json:"architecture"`
+	OS           string `json:"os"`
+}
+
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      digest.Digest     `json:"digest"`
+	Size        int64             `json:"size"`
+	Platform    *ociPlatform      `json:"platform,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type ociIndex struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	MediaType     string          `json:"mediaType,omitempty"`
+	Manifests     []ociDescriptor `json:"manifests"`
+}
+
+type ociManifest struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	MediaType     string          `json:"mediaType,omitempty"`
+	Config        ociDescriptor   `json:"config"`
+	Layers        []ociDescriptor `json:"layers"`
+}
+
+type ociExporter struct {
+	*tarexporter
+}
+
+// NewOCIExporter returns new ImageExporter that saves images in the OCI image
+// layout. Images are loaded in any format understood by NewTarExporter.
+func NewOCIExporter(is image.Store, ls layer.Store, rs reference.Store, loggerImgEvent LogImageEvent) image.Exporter {
+	return &ociExporter{
+		tarexporter: &tarexporter{
+			is:             is,
+			ls:             ls,
+			rs:             rs,
+			loggerImgEvent: loggerImgEvent,
+		},
+	}
+}
+
+func ociBlobPath(dgst digest.Digest) string {
+	return ociBlobsDirName + "/" + string(dgst.Algorithm()) + "/" + dgst.Hex()
+}
@@ -0,0 +1,217 @@
+package tarexport
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/digest"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+	"github.com/docker/docker/pkg/progress"
+	"github.com/docker/docker/reference"
+)
+
+// isOCILayout returns true if dir holds an OCI image layout.
+func isOCILayout(dir string) (bool, error) {
+	layoutPath, err := safePath(dir, ociLayoutFileName)
+	if err != nil {
+		return false, err
+	}
+	if _, err := os.Stat(layoutPath); err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (l *tarexporter) ociLoad(tmpDir string, outStream io.Writer, progressOutput progress.Output) error {
+	var layout ociLayout
+	if err := readOCIJSON(tmpDir, ociLayoutFileName, &layout); err != nil {
+		return err
+	}
+	if layout.ImageLayoutVersion != ociImageLayoutVersion {
+		return fmt.Errorf("unsupported OCI image layout version %q", layout.ImageLayoutVersion)
+	}
+
+	var index ociIndex
+	if err := readOCIJSON(tmpDir, ociIndexFileName, &index); err != nil {
+		return err
+	}
+
+	manifests, err := ociManifestDescriptors(tmpDir, index)
+	if err != nil {
+		return err
+	}
+
+	var imageIDsStr string
+	var imageRefCount int
+	loaded := make(map[digest.Digest]image.ID)
+
+	for _, descr := range manifests {
+		imgID, ok := loaded[descr.Digest]
+		if !ok {
+			if imgID, err = l.ociLoadImage(tmpDir, descr, progressOutput); err != nil {
+				return err
+			}
+			loaded[descr.Digest] = imgID
+			imageIDsStr += fmt.Sprintf("Loaded image ID: %s\n", imgID)
+			l.loggerImgEvent.LogImageEvent(imgID.String(), imgID.String(), "load")
+		}
+
+		ref := ociReference(descr)
+		if ref == nil {
+			continue
+		}
+		l.setLoadedTag(ref, imgID, outStream)
+		outStream.Write([]byte(fmt.Sprintf("Loaded image: %s\n", ref)))
+		imageRefCount++
+	}
+
+	if imageRefCount == 0 {
+		outStream.Write([]byte(imageIDsStr))
+	}
+
+	return nil
+}
+
+// ociManifestDescriptors returns the image manifests referenced by index,
+// descending into nested indexes.
+func ociManifestDescriptors(dir string, index ociIndex) ([]ociDescriptor, error) {
+	var manifests []ociDescriptor
+	for _, descr := range index.Manifests {
+		switch descr.MediaType {
+		case ociMediaTypeManifest:
+			manifests = append(manifests, descr)
+		case ociMediaTypeIndex:
+			var nested ociIndex
+			if err := readOCIBlobJSON(dir, descr.Digest, &nested); err != nil {
+				return nil, err
+			}
+			nestedManifests, err := ociManifestDescriptors(dir, nested)
+			if err != nil {
+				return nil, err
+			}
+			// Names given to the nested index apply to its manifests.
+			for _, m := range nestedManifests {
+				if m.Annotations == nil {
+					m.Annotations = descr.Annotations
+				}
+				manifests = append(manifests, m)
+			}
+		default:
+			return nil, fmt.Errorf("unsupported media type %q in OCI image index", descr.MediaType)
+		}
+	}
+	return manifests, nil
+}
+
+// ociReference returns the tagged reference recorded for a manifest in the
+// index, or nil if the manifest has no usable name.
+func ociReference(descr ociDescriptor) reference.NamedTagged {
+	for _, name := range []string{descr.Annotations[ociAnnotationImageName], descr.Annotations[ociAnnotationRefName]} {
+		if name == "" {
+			continue
+		}
+		named, err := reference.ParseNamed(name)
+		if err != nil {
+			continue
+		}
+		if tagged, ok := named.(reference.NamedTagged); ok {
+			return tagged
+		}
+	}
+	return nil
+}
+
+func (l *tarexporter) ociLoadImage(dir string, manifestDescr ociDescriptor, progressOutput progress.Output) (image.ID, error) {
+	var manifest ociManifest
+	if err := readOCIBlobJSON(dir, manifestDescr.Digest, &manifest); err != nil {
+		return "", err
+	}
+
+	config, err := readOCIBlob(dir, manifest.Config.Digest)
+	if err != nil {
+		return "", err
+	}
+	img, err := image.NewFromJSON(config)
+	if err != nil {
+		return "", err
+	}
+
+	if expected, actual := len(manifest.Layers), len(img.RootFS.DiffIDs); expected != actual {
+		return "", fmt.Errorf("invalid manifest, layers length mismatch: expected %d, got %d", expected, actual)
+	}
+
+	rootFS := *img.RootFS
+	rootFS.DiffIDs = nil
+	for i, diffID := range img.RootFS.DiffIDs {
+		layerPath, err := safePath(dir, ociBlobPath(manifest.Layers[i].Digest))
+		if err != nil {
+			return "", err
+		}
+		r := rootFS
+		r.Append(diffID)
+		newLayer, err := l.ls.Get(r.ChainID())
+		if err != nil {
+			newLayer, err = l.loadLayer(layerPath, rootFS, diffID.String(), distribution.Descriptor{}, progressOutput)
+			if err != nil {
+				return "", err
+			}
+		}
+		defer layer.ReleaseAndLog(l.ls, newLayer)
+		if expected, actual := diffID, newLayer.DiffID(); expected != actual {
+			return "", fmt.Errorf("invalid diffID for layer %d: expected %q, got %q", i, expected, actual)
+		}
+		rootFS.Append(diffID)
+	}
+
+	return l.is.Create(config)
+}
+
+// readOCIBlob reads a blob from the layout in dir and verifies its digest.
+func readOCIBlob(dir string, dgst digest.Digest) ([]byte, error) {
+	if err := dgst.Validate(); err != nil {
+		return nil, err
+	}
+	blobPath, err := safePath(dir, ociBlobPath(dgst))
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(blobPath)
+	if err != nil {
+		return nil, err
+	}
+	if actual := digest.FromBytes(data); actual != dgst {
+		return nil, fmt.Errorf("invalid blob %s: digest is %s", dgst, actual)
+	}
+	return data, nil
+}
+
+// readOCIJSON decodes the file name of the layout in dir into v.
+func readOCIJSON(dir, name string, v interface{}) error {
+	p, err := safePath(dir, filepath.FromSlash(name))
+	if err != nil {
+		return err
+	}
+	data, err := ioutil.ReadFile(p)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// readOCIBlobJSON decodes a blob of the layout in dir into v.
+func readOCIBlobJSON(dir string, dgst digest.Digest, v interface{}) error {
+	data, err := readOCIBlob(dir, dgst)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
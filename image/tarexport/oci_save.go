@@ -0,0 +1,208 @@
+package tarexport
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/docker/distribution/digest"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+	"github.com/docker/docker/pkg/archive"
+)
+
+type ociSaveSession struct {
+	*tarexporter
+	outDir string
+	images map[image.ID]*imageDescriptor
+	// savedBlobs caches the descriptor of every blob written, to avoid
+	// duplicates.
+	savedBlobs map[digest.Digest]ociDescriptor
+}
+
+func (l *ociExporter) Save(names []string, outStream io.Writer) error {
+	images, err := l.parseNames(names)
+	if err != nil {
+		return err
+	}
+
+	return (&ociSaveSession{tarexporter: l.tarexporter, images: images}).save(outStream)
+}
+
+func (s *ociSaveSession) save(outStream io.Writer) error {
+	s.savedBlobs = make(map[digest.Digest]ociDescriptor)
+
+	tempDir, err := ioutil.TempDir("", "docker-export-oci-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tempDir)
+	s.outDir = tempDir
+
+	index := ociIndex{
+		SchemaVersion: 2,
+		MediaType:     ociMediaTypeIndex,
+		Manifests:     []ociDescriptor{},
+	}
+
+	for id, imageDescr := range s.images {
+		manifestDescr, err := s.saveImage(id)
+		if err != nil {
+			return err
+		}
+
+		if len(imageDescr.refs) == 0 {
+			index.Manifests = append(index.Manifests, manifestDescr)
+		}
+		for _, ref := range imageDescr.refs {
+			d := manifestDescr
+			d.Annotations = map[string]string{
+				ociAnnotationRefName:   ref.Tag(),
+				ociAnnotationImageName: ref.FullName() + ":" + ref.Tag(),
+			}
+			index.Manifests = append(index.Manifests, d)
+		}
+
+		s.tarexporter.loggerImgEvent.LogImageEvent(id.String(), id.String(), "save")
+	}
+
+	if err := s.writeJSON(ociLayoutFileName, ociLayout{ImageLayoutVersion: ociImageLayoutVersion}); err != nil {
+		return err
+	}
+	if err := s.writeJSON(ociIndexFileName, index); err != nil {
+		return err
+	}
+
+	fs, err := archive.Tar(tempDir, archive.Uncompressed)
+	if err != nil {
+		return err
+	}
+	defer fs.Close()
+
+	_, err = io.Copy(outStream, fs)
+	return err
+}
+
+// saveImage writes the layers, config and manifest of an image as blobs and
+// returns the descriptor of the manifest.
+func (s *ociSaveSession) saveImage(id image.ID) (ociDescriptor, error) {
+	img, err := s.is.Get(id)
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+
+	if len(img.RootFS.DiffIDs) == 0 {
+		return ociDescriptor{}, fmt.Errorf("empty export - not implemented")
+	}
+
+	manifest := ociManifest{
+		SchemaVersion: 2,
+		MediaType:     ociMediaTypeManifest,
+	}
+
+	rootFS := *img.RootFS
+	rootFS.DiffIDs = nil
+	for _, diffID := range img.RootFS.DiffIDs {
+		rootFS.Append(diffID)
+		layerDescr, err := s.saveLayer(rootFS.ChainID())
+		if err != nil {
+			return ociDescriptor{}, err
+		}
+		manifest.Layers = append(manifest.Layers, layerDescr)
+	}
+
+	manifest.Config, err = s.saveBlob(ociMediaTypeConfig, img.RawJSON())
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+
+	manifestJSON, err := json.Marshal(manifest)
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	manifestDescr, err := s.saveBlob(ociMediaTypeManifest, manifestJSON)
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	manifestDescr.Platform = &ociPlatform{
+		Architecture: img.Architecture,
+		OS:           img.OS,
+	}
+	return manifestDescr, nil
+}
+
+// saveLayer writes the uncompressed tar stream of a layer as a blob. The
+// digest of the blob is the DiffID of the layer.
+func (s *ociSaveSession) saveLayer(id layer.ChainID) (ociDescriptor, error) {
+	l, err := s.ls.Get(id)
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	defer layer.ReleaseAndLog(s.ls, l)
+
+	dgst := digest.Digest(l.DiffID())
+	if descr, exists := s.savedBlobs[dgst]; exists {
+		return descr, nil
+	}
+
+	blobPath := filepath.Join(s.outDir, filepath.FromSlash(ociBlobPath(dgst)))
+	if err := os.MkdirAll(filepath.Dir(blobPath), 0755); err != nil {
+		return ociDescriptor{}, err
+	}
+	blobFile, err := os.Create(blobPath)
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	defer blobFile.Close()
+
+	arch, err := l.TarStream()
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	defer arch.Close()
+
+	size, err := io.Copy(blobFile, arch)
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+
+	descr := ociDescriptor{
+		MediaType: ociMediaTypeLayer,
+		Digest:    dgst,
+		Size:      size,
+	}
+	s.savedBlobs[dgst] = descr
+	return descr, nil
+}
+
+func (s *ociSaveSession) saveBlob(mediaType string, data []byte) (ociDescriptor, error) {
+	descr := ociDescriptor{
+		MediaType: mediaType,
+		Digest:    digest.FromBytes(data),
+		Size:      int64(len(data)),
+	}
+	if _, exists := s.savedBlobs[descr.Digest]; exists {
+		return descr, nil
+	}
+
+	blobPath := filepath.Join(s.outDir, filepath.FromSlash(ociBlobPath(descr.Digest)))
+	if err := os.MkdirAll(filepath.Dir(blobPath), 0755); err != nil {
+		return ociDescriptor{}, err
+	}
+	if err := ioutil.WriteFile(blobPath, data, 0644); err != nil {
+		return ociDescriptor{}, err
+	}
+	s.savedBlobs[descr.Digest] = descr
+	return descr, nil
+}
+
+func (s *ociSaveSession) writeJSON(name string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(s.outDir, name), data, 0644)
+}
@@ -380,3 +380,44 @@ func (s *DockerSuite) TestSaveLoadNoTag(c *check.C) {
 	c.Assert(out, checker.Contains, "Loaded image: "+name+":latest")
 	c.Assert(out, checker.Not(checker.Contains), "Loaded image ID:")
 }
+
+func (s *DockerSuite) TestSaveLoadOCILayout(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+
+	name := "saveloadoci"
+	_, err := buildImage(name, "FROM busybox\nENV foo=bar", true)
+	c.Assert(err, checker.IsNil, check.Commentf("%v", err))
+
+	before := inspectField(c, name, "Id")
+
+	tmpDir, err := ioutil.TempDir("", "save-load-oci-test")
+	c.Assert(err, checker.IsNil)
+	defer os.RemoveAll(tmpDir)
+
+	out, _, err := runCommandPipelineWithOutput(
+		exec.Command(dockerBinary, "save", "--format", "oci", name),
+		exec.Command("tar", "-xf", "-", "-C", tmpDir))
+	c.Assert(err, checker.IsNil, check.Commentf("failed to save repo: %s, %v", out, err))
+
+	for _, f := range []string{"oci-layout", "index.json", filepath.Join("blobs", "sha256")} {
+		_, err := os.Stat(filepath.Join(tmpDir, f))
+		c.Assert(err, checker.IsNil, check.Commentf("%s missing from OCI layout", f))
+	}
+
+	dockerCmd(c, "rmi", name)
+
+	out, _, err = runCommandPipelineWithOutput(
+		exec.Command(dockerBinary, "save", "--format", "oci", "busybox"),
+		exec.Command(dockerBinary, "load"))
+	c.Assert(err, checker.IsNil, check.Commentf("failed to save and load busybox: %s, %v", out, err))
+	c.Assert(out, checker.Contains, "Loaded image: busybox:latest")
+
+	out, _, err = runCommandPipelineWithOutput(
+		exec.Command("tar", "-cf", "-", "-C", tmpDir, "."),
+		exec.Command(dockerBinary, "load"))
+	c.Assert(err, checker.IsNil, check.Commentf("failed to load OCI layout: %s, %v", out, err))
+	c.Assert(out, checker.Contains, "Loaded image: "+name+":latest")
+
+	after := inspectField(c, name, "Id")
+	c.Assert(after, checker.Equals, before, check.Commentf("image ID changed after OCI save / load"))
+}